@@ -1,10 +1,28 @@
 package dexec
 
-import "golang.org/x/sys/windows"
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows"
+)
 
 func (c *Cmd) canInterrupt() bool {
 	return c != nil &&
 		c.Cmd != nil &&
+		c.Process != nil &&
 		c.SysProcAttr != nil &&
 		(c.SysProcAttr.CreationFlags&windows.CREATE_NEW_PROCESS_GROUP) != 0
 }
+
+// sendTerminationSignal dispatches CTRL_BREAK_EVENT for Interrupt/Terminate,
+// since Windows console control events don't distinguish between the two,
+// and falls back to an unconditional TerminateProcess for Kill.
+func (c *Cmd) sendTerminationSignal(sig TerminationSignal) error {
+	switch sig {
+	case Interrupt, Terminate:
+		return windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(c.Process.Pid))
+	case Kill:
+		return c.Process.Kill()
+	default:
+		return errors.Errorf("dexec: unknown TerminationSignal %v", sig)
+	}
+}
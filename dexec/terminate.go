@@ -0,0 +1,134 @@
+package dexec
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TerminationSignal is a portable name for one rung of a TerminationPolicy's
+// escalation ladder. Cmd.Terminate translates it to the right OS-level
+// mechanism: SIGINT/SIGTERM/SIGKILL sent to the process group on Unix, or
+// CTRL_BREAK_EVENT/TerminateProcess on Windows.
+type TerminationSignal int
+
+const (
+	// Interrupt asks the process to shut down, e.g. SIGINT or CTRL_BREAK_EVENT.
+	Interrupt TerminationSignal = iota
+	// Terminate asks the process to shut down more insistently, e.g. SIGTERM.
+	Terminate
+	// Kill ends the process unconditionally, e.g. SIGKILL or TerminateProcess.
+	Kill
+)
+
+func (s TerminationSignal) String() string {
+	switch s {
+	case Interrupt:
+		return "interrupt"
+	case Terminate:
+		return "terminate"
+	case Kill:
+		return "kill"
+	default:
+		return fmt.Sprintf("TerminationSignal(%d)", int(s))
+	}
+}
+
+// TerminationStep is one rung of a TerminationPolicy: wait After since the
+// previous step (or since Terminate was called, for the first step), then
+// send Signal if the process hasn't exited yet.
+type TerminationStep struct {
+	Signal TerminationSignal
+	After  time.Duration
+}
+
+// TerminationPolicy describes an ordered escalation ladder for gracefully
+// shutting down a process: an initial polite signal, followed by
+// progressively more forceful ones if the process doesn't exit in time.
+type TerminationPolicy []TerminationStep
+
+// DefaultTerminationPolicy requests an Interrupt immediately, escalates to
+// Terminate after 10s, and to an unconditional Kill after a further 10s
+// (i.e. 20s after Terminate was called).
+func DefaultTerminationPolicy() TerminationPolicy {
+	return TerminationPolicy{
+		{Signal: Interrupt, After: 0},
+		{Signal: Terminate, After: 10 * time.Second},
+		{Signal: Kill, After: 10 * time.Second},
+	}
+}
+
+// ErrProcessGroupRequired is returned by Terminate when the Cmd wasn't
+// started in its own process group, so Interrupt/Terminate can't be
+// delivered to it alone: on Unix set SysProcAttr.Setpgid, on Windows set
+// SysProcAttr.CreationFlags |= CREATE_NEW_PROCESS_GROUP.
+var ErrProcessGroupRequired = errors.New("dexec: Cmd.Terminate requires the process to have been started in its own process group")
+
+// Terminate walks policy (DefaultTerminationPolicy if empty), sending each
+// step's signal in turn; before each step it waits up to that step's After
+// (since the previous signal) for the process to exit, and skips the rest of
+// the ladder if it does. It returns as soon as the
+// process has exited (the error, if any, from Wait), or ctx.Err() if ctx is
+// done first.
+func (c *Cmd) Terminate(ctx context.Context, policy TerminationPolicy) error {
+	if !c.canInterrupt() {
+		return ErrProcessGroupRequired
+	}
+	if len(policy) == 0 {
+		policy = DefaultTerminationPolicy()
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- c.Wait() }()
+
+	for i, step := range policy {
+		if i > 0 || step.After > 0 {
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(step.After):
+			}
+		}
+		if err := c.sendTerminationSignal(step.Signal); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WatchContext spawns a goroutine that calls c.Terminate with policy as soon
+// as ctx is done, so that a goroutine-managed subprocess (e.g. one started
+// from a dgroup worker) inherits the escalation ladder when the parent
+// context is cancelled, without its owner needing to wire that up by hand.
+//
+// The returned stop function must be called once the process has exited on
+// its own--typically right after the caller's own call to Wait returns--so
+// the watcher goroutine doesn't sit blocked on ctx for the rest of ctx's
+// lifetime (e.g. a server's root context watching a long chain of
+// short-lived subprocesses).
+func (c *Cmd) WatchContext(ctx context.Context, policy TerminationPolicy) (stop func()) {
+	stopped := make(chan struct{})
+	var once sync.Once
+	stop = func() { once.Do(func() { close(stopped) }) }
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = c.Terminate(context.Background(), policy)
+		case <-stopped:
+		}
+	}()
+
+	return stop
+}
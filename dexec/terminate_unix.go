@@ -0,0 +1,34 @@
+//go:build !windows
+
+package dexec
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+func (c *Cmd) canInterrupt() bool {
+	return c != nil &&
+		c.Cmd != nil &&
+		c.Process != nil &&
+		c.SysProcAttr != nil &&
+		c.SysProcAttr.Setpgid
+}
+
+// sendTerminationSignal signals the whole process group that Setpgid
+// created, not just the direct child, so that it reaches grandchildren too.
+func (c *Cmd) sendTerminationSignal(sig TerminationSignal) error {
+	var unixSig syscall.Signal
+	switch sig {
+	case Interrupt:
+		unixSig = syscall.SIGINT
+	case Terminate:
+		unixSig = syscall.SIGTERM
+	case Kill:
+		unixSig = syscall.SIGKILL
+	default:
+		return errors.Errorf("dexec: unknown TerminationSignal %v", sig)
+	}
+	return syscall.Kill(-c.Process.Pid, unixSig)
+}
@@ -0,0 +1,107 @@
+//go:build !windows
+
+package dexec
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func newGroupLeaderCmd(t *testing.T, name string, args ...string) *Cmd {
+	t.Helper()
+	cmd := &Cmd{Cmd: exec.Command(name, args...)}
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+func TestTerminate_ExitsOnInterrupt(t *testing.T) {
+	// "sleep 100" ignores nothing in particular, but SIGINT/SIGTERM both
+	// terminate it by default, so Terminate should succeed on the very
+	// first (Interrupt) rung without ever reaching Kill.
+	cmd := newGroupLeaderCmd(t, "sleep", "100")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("could not start sleep(1): %v", err)
+	}
+
+	start := time.Now()
+	err := cmd.Terminate(context.Background(), TerminationPolicy{
+		{Signal: Interrupt, After: 0},
+		{Signal: Terminate, After: 5 * time.Second},
+		{Signal: Kill, After: 5 * time.Second},
+	})
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Terminate returned nil error; expected the error from Wait on a signal-killed process")
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("Terminate took %v, want it to exit promptly on the Interrupt rung", elapsed)
+	}
+}
+
+func TestTerminate_WithoutProcessGroupFails(t *testing.T) {
+	cmd := &Cmd{Cmd: exec.Command("sleep", "100")}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("could not start sleep(1): %v", err)
+	}
+	defer cmd.Process.Kill() //nolint:errcheck
+
+	if err := cmd.Terminate(context.Background(), DefaultTerminationPolicy()); err != ErrProcessGroupRequired {
+		t.Fatalf("Terminate error = %v, want ErrProcessGroupRequired", err)
+	}
+}
+
+func TestWatchContext_StopPreventsLeakAfterNaturalExit(t *testing.T) {
+	cmd := newGroupLeaderCmd(t, "true")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("could not start true(1): %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stop := cmd.WatchContext(ctx, DefaultTerminationPolicy())
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	stop()
+	// If stop didn't work, the watcher goroutine would later call
+	// cmd.Terminate, which calls cmd.Wait again--that second Wait call
+	// would panic ("exec: Wait was already called"). Cancelling ctx after
+	// stop must therefore be a no-op.
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestDefaultTerminationPolicy_Durations(t *testing.T) {
+	policy := DefaultTerminationPolicy()
+	if len(policy) != 3 {
+		t.Fatalf("len(DefaultTerminationPolicy()) = %d, want 3", len(policy))
+	}
+	if policy[0].Signal != Interrupt || policy[0].After != 0 {
+		t.Errorf("step 0 = %+v, want Interrupt at 0", policy[0])
+	}
+	if policy[1].Signal != Terminate || policy[1].After != 10*time.Second {
+		t.Errorf("step 1 = %+v, want Terminate at 10s", policy[1])
+	}
+	if policy[2].Signal != Kill || policy[2].After != 10*time.Second {
+		t.Errorf("step 2 = %+v, want Kill 10s after Terminate (20s total)", policy[2])
+	}
+}
+
+func TestTerminationSignal_String(t *testing.T) {
+	cases := map[TerminationSignal]string{
+		Interrupt:             "interrupt",
+		Terminate:             "terminate",
+		Kill:                  "kill",
+		TerminationSignal(99): "TerminationSignal(99)",
+	}
+	for sig, want := range cases {
+		if got := sig.String(); got != want {
+			t.Errorf("TerminationSignal(%d).String() = %q, want %q", sig, got, want)
+		}
+	}
+}
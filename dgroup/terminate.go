@@ -0,0 +1,23 @@
+package dgroup
+
+import (
+	"context"
+
+	"github.com/telepresenceio/dlib/v2/dexec"
+)
+
+// GoCmd starts cmd and runs it as a worker goroutine in g under name, using
+// policy to escalate through cmd.Terminate as soon as g's context is
+// cancelled--so a goroutine-managed subprocess inherits the same graceful
+// shutdown ladder as the rest of the group, instead of every caller having
+// to wire dexec.Cmd.WatchContext up by hand.
+func (g *Group) GoCmd(name string, cmd *dexec.Cmd, policy dexec.TerminationPolicy) {
+	g.Go(name, func(ctx context.Context) error {
+		if err := cmd.Start(); err != nil {
+			return err
+		}
+		stop := cmd.WatchContext(ctx, policy)
+		defer stop()
+		return cmd.Wait()
+	})
+}
@@ -0,0 +1,136 @@
+package dlog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const correlationIDField = "correlation_id"
+
+type correlationIDKey struct{}
+
+type correlationParentLoggerKey struct{}
+
+//nolint:gochecknoglobals // configurable hook, intentionally global like WrapLogrus
+var correlationIDGenerator atomic.Value // func() string
+
+//nolint:gochecknoglobals // configurable list of headers checked by HTTPMiddleware/the gRPC interceptors
+var correlationIDHeaders atomic.Value // []string
+
+func init() {
+	correlationIDGenerator.Store(newULID)
+	correlationIDHeaders.Store([]string{"X-Request-ID", "X-Correlation-ID"})
+}
+
+// WithCorrelationIDGenerator overrides how a correlation ID is generated
+// by NewCorrelationID, and by HTTPMiddleware or the gRPC interceptors when
+// a request carries none, so that callers can substitute a UUIDv7 generator or one
+// that derives an ID from an OpenTelemetry trace ID instead of the default
+// ULID.
+func WithCorrelationIDGenerator(gen func() string) {
+	correlationIDGenerator.Store(gen)
+}
+
+// SetCorrelationIDHeaders overrides, in priority order, which headers
+// HTTPMiddleware and the gRPC interceptors check for an inbound correlation
+// ID. The default is {"X-Request-ID", "X-Correlation-ID"}.
+func SetCorrelationIDHeaders(headers ...string) {
+	correlationIDHeaders.Store(append([]string(nil), headers...))
+}
+
+// NewCorrelationID generates a correlation ID using the configured
+// generator (see WithCorrelationIDGenerator), without attaching it to a
+// context. It's exported so that out-of-package integrations--e.g. the gRPC
+// interceptors in dlog/dloggrpc--can generate IDs the same way
+// HTTPMiddleware does.
+func NewCorrelationID() string {
+	return generateCorrelationID()
+}
+
+// CorrelationIDHeaders returns the headers currently configured (see
+// SetCorrelationIDHeaders) for HTTPMiddleware and the gRPC interceptors to
+// check, in priority order.
+func CorrelationIDHeaders() []string {
+	return configuredCorrelationIDHeaders()
+}
+
+func generateCorrelationID() string {
+	return correlationIDGenerator.Load().(func() string)()
+}
+
+func configuredCorrelationIDHeaders() []string {
+	return correlationIDHeaders.Load().([]string)
+}
+
+// WithCorrelationID attaches id as ctx's correlation ID, and also returns a
+// context whose Logger (see WithLogger) has been given a "correlation_id"
+// field, so every dlog.Info/Error/... call made against it includes id with
+// no extra work at the call site.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	parent := getLogger(ctx)
+	ctx = context.WithValue(ctx, correlationIDKey{}, id)
+	ctx = context.WithValue(ctx, correlationParentLoggerKey{}, parent)
+	return WithLogger(ctx, parent.WithField(correlationIDField, id))
+}
+
+// CorrelationID returns the correlation ID attached to ctx by
+// WithCorrelationID, or "" if none has been set.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithoutCorrelationID returns a copy of ctx with its correlation ID
+// cleared, restoring the Logger to what it was before WithCorrelationID
+// added the "correlation_id" field, for handing off to work that shouldn't
+// be associated with it.
+func WithoutCorrelationID(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, correlationIDKey{}, "")
+	if parent, ok := ctx.Value(correlationParentLoggerKey{}).(Logger); ok && parent != nil {
+		ctx = WithLogger(ctx, parent)
+	}
+	return ctx
+}
+
+// newULID is the default correlation ID generator: a 48-bit millisecond
+// timestamp followed by 80 bits of randomness, Crockford base32 encoded, in
+// the same shape as a github.com/oklog/ulid.ULID but without the dependency.
+func newULID() string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(time.Now().UnixMilli())<<16)
+	if _, err := rand.Read(id[6:]); err != nil {
+		// crypto/rand.Read on a fixed-size buffer only fails if the OS RNG
+		// is broken, at which point there's nothing better to fall back to.
+		panic(err)
+	}
+	return encodeCrockford32(id[:])
+}
+
+const crockford32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// encodeCrockford32 renders a 16-byte (128-bit) value as the 26-character
+// Crockford base32 encoding ULIDs use.
+func encodeCrockford32(b []byte) string {
+	var sb strings.Builder
+	sb.Grow(26)
+	var acc uint64
+	// ULIDs left-pad the 128 bits with two zero bits, for 130 bits in 26
+	// whole characters.
+	bits := 2
+	for _, by := range b {
+		acc = acc<<8 | uint64(by)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			sb.WriteByte(crockford32Alphabet[(acc>>uint(bits))&0x1f])
+		}
+	}
+	if bits > 0 {
+		sb.WriteByte(crockford32Alphabet[(acc<<uint(5-bits))&0x1f])
+	}
+	return sb.String()
+}
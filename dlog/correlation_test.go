@@ -0,0 +1,71 @@
+package dlog_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/telepresenceio/dlib/v2/dlog"
+)
+
+var ulidPattern = regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{26}$`)
+
+func TestNewCorrelationID_LooksLikeAULID(t *testing.T) {
+	a := dlog.NewCorrelationID()
+	b := dlog.NewCorrelationID()
+
+	if !ulidPattern.MatchString(a) {
+		t.Fatalf("NewCorrelationID() = %q, want a 26-char Crockford base32 string", a)
+	}
+	if a == b {
+		t.Fatalf("two calls to NewCorrelationID() returned the same ID: %q", a)
+	}
+}
+
+func TestWithCorrelationID_RoundTrip(t *testing.T) {
+	rec := newRecordingLogger()
+	ctx := dlog.WithLogger(context.Background(), rec)
+
+	ctx = dlog.WithCorrelationID(ctx, "abc123")
+	if got := dlog.CorrelationID(ctx); got != "abc123" {
+		t.Fatalf("CorrelationID() = %q, want %q", got, "abc123")
+	}
+
+	ctx = dlog.WithoutCorrelationID(ctx)
+	if got := dlog.CorrelationID(ctx); got != "" {
+		t.Fatalf("CorrelationID() after WithoutCorrelationID = %q, want \"\"", got)
+	}
+}
+
+func TestHTTPMiddleware_HeaderPriority(t *testing.T) {
+	dlog.SetCorrelationIDHeaders("X-Request-ID", "X-Correlation-ID")
+
+	var gotID string
+	h := dlog.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = dlog.CorrelationID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "from-request-id")
+	req.Header.Set("X-Correlation-ID", "from-correlation-id")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if gotID != "from-request-id" {
+		t.Fatalf("correlation ID = %q, want the higher-priority X-Request-ID value", gotID)
+	}
+}
+
+func TestHTTPMiddleware_GeneratesIDWhenMissing(t *testing.T) {
+	var gotID string
+	h := dlog.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = dlog.CorrelationID(r.Context())
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if gotID == "" {
+		t.Fatal("HTTPMiddleware did not generate a correlation ID when no header was present")
+	}
+}
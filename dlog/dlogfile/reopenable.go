@@ -0,0 +1,99 @@
+// Package dlogfile provides an io.Writer that can be pointed at a log file
+// and later told to reopen that file, so that external log rotators (e.g.
+// logrotate) can rename the file out from under a long-running process
+// without the process ever writing to a deleted inode.
+package dlogfile
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// ReopenableWriter is an io.Writer backed by a file on disk that can be
+// swapped out for a freshly opened handle to the same path via Reopen,
+// without losing any in-flight writes.
+type ReopenableWriter struct {
+	path string
+	flag int
+	perm os.FileMode
+
+	file atomic.Pointer[os.File]
+
+	// writeMu serializes writes against Reopen so that a write is never
+	// split across the old and new file handles.
+	writeMu sync.Mutex
+}
+
+// Open creates a ReopenableWriter backed by the file at path, opened with
+// flag and perm (as os.OpenFile). The flag should normally include
+// os.O_APPEND|os.O_CREATE|os.O_WRONLY.
+func Open(path string, flag int, perm os.FileMode) (*ReopenableWriter, error) {
+	f, err := os.OpenFile(path, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	w := &ReopenableWriter{path: path, flag: flag, perm: perm}
+	w.file.Store(f)
+	return w, nil
+}
+
+// Write implements io.Writer. It is safe to call concurrently with itself and
+// with Reopen.
+func (w *ReopenableWriter) Write(p []byte) (int, error) {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	return w.file.Load().Write(p)
+}
+
+// Reopen opens the file at the writer's path anew--picking up a fresh inode
+// if it was renamed or removed out from under the old handle--atomically
+// swaps it in, and closes the previous handle once any write in flight has
+// drained.
+func (w *ReopenableWriter) Reopen() error {
+	newFile, err := os.OpenFile(w.path, w.flag, w.perm)
+	if err != nil {
+		return err
+	}
+	w.writeMu.Lock()
+	old := w.file.Swap(newFile)
+	w.writeMu.Unlock()
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// Close closes the current underlying file handle.
+func (w *ReopenableWriter) Close() error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	return w.file.Load().Close()
+}
+
+// Path returns the filesystem path this writer was opened against.
+func (w *ReopenableWriter) Path() string {
+	return w.path
+}
+
+// Reopener is anything that can reopen itself, e.g. a *ReopenableWriter or a
+// MultiReopener of several of them.
+type Reopener interface {
+	Reopen() error
+}
+
+// MultiReopener aggregates several ReopenableWriters--e.g. stdout, stderr,
+// and an access log--so that a single signal can reopen all of them.
+type MultiReopener []*ReopenableWriter
+
+// Reopen calls Reopen on every writer in the set, returning the first error
+// encountered after attempting all of them.
+func (m MultiReopener) Reopen() error {
+	var firstErr error
+	for _, w := range m {
+		if err := w.Reopen(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
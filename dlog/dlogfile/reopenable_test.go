@@ -0,0 +1,70 @@
+package dlogfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/telepresenceio/dlib/v2/dlog/dlogfile"
+)
+
+func TestReopenableWriter_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	w, err := dlogfile.Open(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	rotated := filepath.Join(dir, "access.log.1")
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if err := w.Reopen(); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write after reopen: %v", err)
+	}
+
+	rotatedContent, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatalf("ReadFile(rotated): %v", err)
+	}
+	if string(rotatedContent) != "before\n" {
+		t.Errorf("rotated file content = %q, want %q", rotatedContent, "before\n")
+	}
+
+	freshContent, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(fresh): %v", err)
+	}
+	if string(freshContent) != "after\n" {
+		t.Errorf("fresh file content = %q, want %q", freshContent, "after\n")
+	}
+}
+
+func TestMultiReopener(t *testing.T) {
+	dir := t.TempDir()
+	var mr dlogfile.MultiReopener
+	paths := []string{"stdout.log", "stderr.log"}
+	for _, p := range paths {
+		w, err := dlogfile.Open(filepath.Join(dir, p), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatalf("Open(%s): %v", p, err)
+		}
+		defer w.Close()
+		mr = append(mr, w)
+	}
+	if err := mr.Reopen(); err != nil {
+		t.Fatalf("MultiReopener.Reopen: %v", err)
+	}
+}
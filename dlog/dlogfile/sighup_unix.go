@@ -0,0 +1,60 @@
+//go:build !windows
+
+package dlogfile
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/telepresenceio/dlib/v2/dgroup"
+	"github.com/telepresenceio/dlib/v2/dlog"
+)
+
+// InstallSIGHUPHandler starts a bare goroutine that calls Reopen on each of
+// ws whenever the process receives SIGHUP, logging any error through
+// dlog.Error. The goroutine exits when ctx is done.
+//
+// Callers that already run their process as a dgroup.Group should prefer
+// InstallSIGHUPHandlerInGroup instead, so the watcher is tracked/restarted
+// like the rest of the group's workers rather than living outside it.
+func InstallSIGHUPHandler(ctx context.Context, ws ...Reopener) {
+	// Subscribe before returning, so a SIGHUP sent right after this call is
+	// handled instead of taking its default action of ending the process.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		_ = watchSIGHUP(ctx, sigCh, ws...)
+	}()
+}
+
+// InstallSIGHUPHandlerInGroup is like InstallSIGHUPHandler, but runs the
+// watcher as a named worker in g instead of a bare goroutine, so it shuts
+// down alongside--and its failures are reported through--the rest of g.
+func InstallSIGHUPHandlerInGroup(g *dgroup.Group, name string, ws ...Reopener) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	g.Go(name, func(ctx context.Context) error {
+		return watchSIGHUP(ctx, sigCh, ws...)
+	})
+}
+
+// watchSIGHUP blocks reading SIGHUPs from sigCh (already subscribed via
+// signal.Notify), calling Reopen on each of ws each time one is received,
+// until ctx is done; it then unsubscribes sigCh.
+func watchSIGHUP(ctx context.Context, sigCh chan os.Signal, ws ...Reopener) error {
+	defer signal.Stop(sigCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sigCh:
+			for _, w := range ws {
+				if err := w.Reopen(); err != nil {
+					dlog.Error(ctx, err)
+				}
+			}
+		}
+	}
+}
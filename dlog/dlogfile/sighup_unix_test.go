@@ -0,0 +1,84 @@
+//go:build !windows
+
+package dlogfile_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/telepresenceio/dlib/v2/dlog/dlogfile"
+)
+
+// signalingReopener wraps a Reopener so the test can block until a real
+// SIGHUP has actually been handled, instead of racing the async handler
+// goroutine with a fixed sleep.
+type signalingReopener struct {
+	inner   dlogfile.Reopener
+	reopend chan struct{}
+}
+
+func (s *signalingReopener) Reopen() error {
+	err := s.inner.Reopen()
+	close(s.reopend)
+	return err
+}
+
+func TestInstallSIGHUPHandler_ReopensOnRealSignal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	w, err := dlogfile.Open(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer w.Close()
+
+	if _, err := w.Write([]byte("before\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	rotated := filepath.Join(dir, "access.log.1")
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	sr := &signalingReopener{inner: w, reopend: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dlogfile.InstallSIGHUPHandler(ctx, sr)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatalf("Kill(SIGHUP): %v", err)
+	}
+
+	select {
+	case <-sr.reopend:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for InstallSIGHUPHandler to react to SIGHUP")
+	}
+
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write after reopen: %v", err)
+	}
+
+	rotatedContent, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatalf("ReadFile(rotated): %v", err)
+	}
+	if string(rotatedContent) != "before\n" {
+		t.Errorf("rotated file content = %q, want %q", rotatedContent, "before\n")
+	}
+
+	freshContent, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile(fresh): %v", err)
+	}
+	if string(freshContent) != "after\n" {
+		t.Errorf("fresh file content = %q, want %q", freshContent, "after\n")
+	}
+}
@@ -0,0 +1,17 @@
+package dlogfile
+
+import (
+	"context"
+
+	"github.com/telepresenceio/dlib/v2/dgroup"
+)
+
+// InstallSIGHUPHandler is a no-op on Windows, which has no SIGHUP signal.
+// It is provided so that callers can use it unconditionally across
+// platforms.
+func InstallSIGHUPHandler(ctx context.Context, ws ...Reopener) {}
+
+// InstallSIGHUPHandlerInGroup is a no-op on Windows, which has no SIGHUP
+// signal. It is provided so that callers can use it unconditionally across
+// platforms.
+func InstallSIGHUPHandlerInGroup(g *dgroup.Group, name string, ws ...Reopener) {}
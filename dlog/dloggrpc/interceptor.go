@@ -0,0 +1,67 @@
+// Package dloggrpc provides gRPC server interceptors that propagate
+// correlation IDs through dlog, mirroring dlog.HTTPMiddleware for gRPC
+// services. It's a separate package from dlog itself so that consumers of
+// dlog who don't use gRPC don't transitively pull in google.golang.org/grpc.
+package dloggrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/telepresenceio/dlib/v2/dlog"
+)
+
+// UnaryServerInterceptor returns a grpc.UnaryServerInterceptor that attaches
+// a correlation ID to the handler's context the same way dlog.HTTPMiddleware
+// does for HTTP requests, reading it from the first configured metadata key
+// present (see dlog.SetCorrelationIDHeaders) and generating one otherwise.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withIncomingCorrelationID(ctx), req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &correlationServerStream{
+			ServerStream: ss,
+			ctx:          withIncomingCorrelationID(ss.Context()),
+		})
+	}
+}
+
+func withIncomingCorrelationID(ctx context.Context) context.Context {
+	id := correlationIDFromMetadata(ctx)
+	if id == "" {
+		id = dlog.NewCorrelationID()
+	}
+	return dlog.WithCorrelationID(ctx, id)
+}
+
+func correlationIDFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, name := range dlog.CorrelationIDHeaders() {
+		if vs := md.Get(name); len(vs) > 0 && vs[0] != "" {
+			return vs[0]
+		}
+	}
+	return ""
+}
+
+// correlationServerStream wraps a grpc.ServerStream to override Context, the
+// way google.golang.org/grpc's own middleware helpers do.
+type correlationServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *correlationServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,151 @@
+package dlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+//nolint:gochecknoglobals // constant
+var jsonLevelNames = [5]string{"error", "warn", "info", "debug", "trace"}
+
+// JSONOption configures a logger created by NewJSONLogger.
+type JSONOption func(*jsonLogger)
+
+// WithMaxLevel sets the maximum level that will be logged; anything more
+// verbose is dropped before it's formatted. The default is LogLevelInfo.
+func WithMaxLevel(level LogLevel) JSONOption {
+	return func(l *jsonLogger) { l.maxLevel = level }
+}
+
+// WithCaller enables or disables reporting the caller's file:line as a
+// "caller" field. It is disabled by default, since computing it isn't free.
+func WithCaller(enabled bool) JSONOption {
+	return func(l *jsonLogger) { l.withCaller = enabled }
+}
+
+type jsonField struct {
+	key   string
+	value any
+}
+
+type jsonLogger struct {
+	GenericImpl
+
+	w          io.Writer
+	writeMu    sync.Mutex
+	fields     []jsonField
+	maxLevel   LogLevel
+	withCaller bool
+}
+
+var _ GenericLogger = &jsonLogger{}
+
+var _ LoggerWithMaxLevel = &jsonLogger{}
+
+// fieldBufPool recycles the *bytes.Buffer used to assemble each line, so the
+// hot logging path doesn't allocate one per call.
+//
+//nolint:gochecknoglobals // sync.Pool is meant to be a package-level global
+var fieldBufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// NewJSONLogger returns a GenericLogger that writes one JSON object per line
+// to w, without depending on logrus. Fields are emitted as "time", "level",
+// "msg", followed by any WithField fields in sorted-key order.
+func NewJSONLogger(w io.Writer, opts ...JSONOption) GenericLogger {
+	l := &jsonLogger{w: w, maxLevel: LogLevelInfo}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.GenericImpl = GenericImpl{PlainLogger: l}
+	return l
+}
+
+func (l *jsonLogger) Helper() {}
+
+func (l *jsonLogger) MaxLevel() LogLevel {
+	return l.maxLevel
+}
+
+func (l *jsonLogger) WithField(key string, value any) Logger {
+	fields := make([]jsonField, len(l.fields)+1)
+	copy(fields, l.fields)
+	fields[len(l.fields)] = jsonField{key: key, value: value}
+	child := &jsonLogger{w: l.w, fields: fields, maxLevel: l.maxLevel, withCaller: l.withCaller}
+	child.GenericImpl = GenericImpl{PlainLogger: child}
+	return child
+}
+
+func (l *jsonLogger) LogMessage(level LogLevel, message string) {
+	if level > l.maxLevel {
+		return
+	}
+
+	buf, _ := fieldBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer fieldBufPool.Put(buf)
+
+	buf.WriteByte('{')
+	appendJSONField(buf, "time", time.Now().Format(time.RFC3339Nano), true)
+	appendJSONField(buf, "level", jsonLevelNames[level], false)
+	appendJSONField(buf, "msg", message, false)
+	if l.withCaller {
+		if frame := getCaller(); frame != nil {
+			appendJSONField(buf, "caller", fmt.Sprintf("%s:%d", frame.File, frame.Line), false)
+		}
+	}
+
+	sorted := make([]jsonField, len(l.fields))
+	copy(sorted, l.fields)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].key < sorted[j].key })
+	for _, f := range sorted {
+		appendJSONField(buf, f.key, f.value, false)
+	}
+	buf.WriteByte('}')
+	buf.WriteByte('\n')
+
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	_, _ = l.w.Write(buf.Bytes())
+}
+
+func appendJSONField(buf *bytes.Buffer, key string, value any, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	kb, err := json.Marshal(key)
+	if err != nil {
+		kb = []byte(`"?"`)
+	}
+	buf.Write(kb)
+	buf.WriteByte(':')
+	vb, err := json.Marshal(value)
+	if err != nil {
+		vb, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	buf.Write(vb)
+}
+
+func (l *jsonLogger) StdLogger(level LogLevel) *log.Logger {
+	return log.New(stdLoggerWriter{logger: l, level: level}, "", 0)
+}
+
+// stdLoggerWriter adapts a jsonLogger (at a fixed level) into an io.Writer,
+// for use by StdLogger.
+type stdLoggerWriter struct {
+	logger *jsonLogger
+	level  LogLevel
+}
+
+func (w stdLoggerWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\n"))
+	w.logger.LogMessage(w.level, msg)
+	return len(p), nil
+}
@@ -0,0 +1,97 @@
+package dlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/telepresenceio/dlib/v2/dlog"
+)
+
+func TestNewJSONLogger_FieldOrderAndContent(t *testing.T) {
+	var buf bytes.Buffer
+	logger := dlog.NewJSONLogger(&buf, dlog.WithMaxLevel(dlog.LogLevelDebug))
+
+	logger.WithField("b", 2).WithField("a", 1).Log(dlog.LogLevelInfo, "hello")
+
+	line := strings.TrimRight(buf.String(), "\n")
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(line), &obj); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, line)
+	}
+	for _, want := range []string{"time", "level", "msg", "a", "b"} {
+		if _, ok := obj[want]; !ok {
+			t.Errorf("missing field %q in %s", want, line)
+		}
+	}
+	if obj["level"] != "info" {
+		t.Errorf("level = %v, want \"info\"", obj["level"])
+	}
+	if obj["msg"] != "hello" {
+		t.Errorf("msg = %v, want \"hello\"", obj["msg"])
+	}
+
+	// "time", then "level", then "msg" must come first and in that order;
+	// the rest (a, b) are sorted by key afterwards.
+	for _, key := range []string{`"time"`, `"level"`, `"msg"`, `"a"`, `"b"`} {
+		if !strings.Contains(line, key) {
+			t.Fatalf("line missing key %s: %s", key, line)
+		}
+	}
+	timeIdx := strings.Index(line, `"time"`)
+	levelIdx := strings.Index(line, `"level"`)
+	msgIdx := strings.Index(line, `"msg"`)
+	aIdx := strings.Index(line, `"a"`)
+	bIdx := strings.Index(line, `"b"`)
+	if !(timeIdx < levelIdx && levelIdx < msgIdx && msgIdx < aIdx && aIdx < bIdx) {
+		t.Fatalf("fields out of order: %s", line)
+	}
+}
+
+func TestNewJSONLogger_EscapesControlCharsAndNewlines(t *testing.T) {
+	var buf bytes.Buffer
+	logger := dlog.NewJSONLogger(&buf)
+
+	logger.Log(dlog.LogLevelError, "line one\nline two\ttabbed")
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("expected exactly one (trailing) newline in output, got: %q", line)
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimRight(line, "\n")), &obj); err != nil {
+		t.Fatalf("output isn't valid JSON: %v\n%s", err, line)
+	}
+	if obj["msg"] != "line one\nline two\ttabbed" {
+		t.Errorf("msg = %q, want the original multiline message round-tripped", obj["msg"])
+	}
+}
+
+func TestNewJSONLogger_WithFieldPreservesMaxLevel(t *testing.T) {
+	logger := dlog.NewJSONLogger(&bytes.Buffer{})
+
+	if _, ok := logger.(dlog.LoggerWithMaxLevel); !ok {
+		t.Fatal("NewJSONLogger result doesn't implement LoggerWithMaxLevel")
+	}
+
+	derived := logger.WithField("k", "v")
+	if _, ok := derived.(dlog.LoggerWithMaxLevel); !ok {
+		t.Fatal("WithField result doesn't implement LoggerWithMaxLevel; callers lose the MaxLevel() gate on every derived logger")
+	}
+}
+
+func TestNewJSONLogger_MaxLevelFiltersMessages(t *testing.T) {
+	var buf bytes.Buffer
+	logger := dlog.NewJSONLogger(&buf, dlog.WithMaxLevel(dlog.LogLevelWarn))
+
+	logger.Log(dlog.LogLevelInfo, "should be dropped")
+	logger.Log(dlog.LogLevelWarn, "should be kept")
+
+	if strings.Contains(buf.String(), "dropped") {
+		t.Errorf("expected LogLevelInfo message to be dropped, got: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("expected LogLevelWarn message to be logged, got: %s", buf.String())
+	}
+}
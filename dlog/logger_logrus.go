@@ -1,6 +1,7 @@
 package dlog
 
 import (
+	"context"
 	"io"
 	"log"
 	"runtime"
@@ -53,6 +54,22 @@ func (l logrusWrapper) LogMessage(level LogLevel, message string) {
 	l.Log(level, message)
 }
 
+var _ callerContextLogger = logrusWrapper{}
+
+// logAt logs as Log does, but first attaches ctx to the entry so that
+// logrusFixCallerHook can recover a caller frame stashed on it (see
+// contextWithCallerFrame) instead of re-deriving one from the current stack.
+func (l logrusWrapper) logAt(ctx context.Context, level LogLevel, message string) {
+	switch le := l.loggerOrEntry.(type) {
+	case *logrus.Logger:
+		le.WithContext(ctx).Log(logrusLevel(level), message)
+	case *logrus.Entry:
+		le.WithContext(ctx).Log(logrusLevel(level), message)
+	default:
+		l.Log(level, message)
+	}
+}
+
 func (l logrusWrapper) StdLogger(level LogLevel) *log.Logger {
 	return log.New(l.WriterLevel(logrusLevel(level)), "", 0)
 }
@@ -105,9 +122,16 @@ func (logrusFixCallerHook) Levels() []logrus.Level {
 }
 
 func (logrusFixCallerHook) Fire(entry *logrus.Entry) error {
-	if entry.Caller != nil && strings.HasPrefix(entry.Caller.Function, dlogPackageDot) {
-		entry.Caller = getCaller()
+	if entry.Caller == nil || !strings.HasPrefix(entry.Caller.Function, dlogPackageDot) {
+		return nil
+	}
+	if entry.Context != nil {
+		if frame, ok := callerFrameFromContext(entry.Context); ok {
+			entry.Caller = frame
+			return nil
+		}
 	}
+	entry.Caller = getCaller()
 	return nil
 }
 
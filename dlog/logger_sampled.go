@@ -0,0 +1,333 @@
+package dlog
+
+import (
+	"container/list"
+	"context"
+	"expvar"
+	"log"
+	"sync"
+	"time"
+)
+
+// SampleOptions configures NewSampledLogger and NewKeyedSampledLogger.
+//
+// Within each Tick window (per level, and for the keyed variant also per
+// key), the first First messages are logged unconditionally, and after that
+// only 1 in every Thereafter is logged. Setting First to 0 disables
+// sampling entirely (everything is logged); setting Thereafter to 0 drops
+// everything past First.
+type SampleOptions struct {
+	First      int
+	Thereafter int
+	Tick       time.Duration
+
+	// MaxKeys bounds the number of independently-tracked keys a keyed
+	// sampled logger will remember before it starts evicting the least
+	// recently used ones. Ignored by NewSampledLogger. Defaults to 1024.
+	MaxKeys int
+
+	// OnDrop, if set, is called for each message the sampler drops, so
+	// callers can expose a dropped_total{level=...} counter; see
+	// ExpvarDropCounter for a ready-made expvar-backed one.
+	OnDrop func(level LogLevel)
+}
+
+func (o SampleOptions) tick() time.Duration {
+	if o.Tick <= 0 {
+		return time.Second
+	}
+	return o.Tick
+}
+
+func (o SampleOptions) maxKeys() int {
+	if o.MaxKeys <= 0 {
+		return 1024
+	}
+	return o.MaxKeys
+}
+
+// allow applies the First/Thereafter rule to the n-th message seen (in the
+// current window) at some level or key.
+func (o SampleOptions) allow(n int) bool {
+	switch {
+	case o.First <= 0:
+		return true
+	case n <= o.First:
+		return true
+	case o.Thereafter <= 0:
+		return false
+	default:
+		return (n-o.First)%o.Thereafter == 0
+	}
+}
+
+// ExpvarDropCounter returns an OnDrop hook that increments an expvar.Map
+// registered under name, with one counter per level (e.g. "error", "warn").
+func ExpvarDropCounter(name string) func(LogLevel) {
+	m := expvar.NewMap(name)
+	return func(level LogLevel) {
+		m.Add(jsonLevelNames[level], 1)
+	}
+}
+
+// sampleBucket tracks per-level counts within the current window.
+type sampleBucket struct {
+	windowStart time.Time
+	counts      [5]int
+}
+
+func (b *sampleBucket) hit(level LogLevel, tick time.Duration, now time.Time) int {
+	if now.Sub(b.windowStart) >= tick {
+		b.windowStart = now
+		b.counts = [5]int{}
+	}
+	b.counts[level]++
+	return b.counts[level]
+}
+
+// sampledLogger decorates a Logger with per-level sampling over fixed Tick
+// windows (see SampleOptions). Each level's count resets all at once when a
+// new window starts; it does not refill gradually the way a token bucket does.
+type sampledLogger struct {
+	inner Logger
+	opts  SampleOptions
+
+	mu     sync.Mutex
+	bucket sampleBucket
+}
+
+var (
+	_ GenericLogger      = &sampledLogger{}
+	_ LoggerWithMaxLevel = &sampledLogger{}
+)
+
+// NewSampledLogger decorates inner with per-level rate limiting: the first
+// opts.First messages per opts.Tick window (default 1s) at a given level are
+// logged, then only 1 in every opts.Thereafter. Dropped messages are
+// short-circuited before they're formatted, so the MaxLevel-check pattern
+// (`if lg.MaxLevel() >= level { lg.Logf(...) }`) continues to avoid
+// unnecessary work even when the sampler--not the level--is what drops a
+// message.
+func NewSampledLogger(inner Logger, opts SampleOptions) Logger {
+	return &sampledLogger{inner: inner, opts: opts}
+}
+
+func (l *sampledLogger) Helper() { l.inner.Helper() }
+
+func (l *sampledLogger) StdLogger(level LogLevel) *log.Logger { return l.inner.StdLogger(level) }
+
+func (l *sampledLogger) MaxLevel() LogLevel {
+	if lwm, ok := l.inner.(LoggerWithMaxLevel); ok {
+		return lwm.MaxLevel()
+	}
+	return LogLevelTrace
+}
+
+func (l *sampledLogger) WithField(key string, value any) Logger {
+	return &sampledLogger{inner: l.inner.WithField(key, value), opts: l.opts}
+}
+
+func (l *sampledLogger) allow(level LogLevel) bool {
+	l.mu.Lock()
+	n := l.bucket.hit(level, l.opts.tick(), time.Now())
+	l.mu.Unlock()
+	ok := l.opts.allow(n)
+	if !ok && l.opts.OnDrop != nil {
+		l.opts.OnDrop(level)
+	}
+	return ok
+}
+
+func (l *sampledLogger) Log(level LogLevel, args ...any) {
+	if l.allow(level) {
+		l.Helper()
+		l.inner.Log(level, args...)
+	}
+}
+
+func (l *sampledLogger) Logf(level LogLevel, format string, args ...any) {
+	if l.allow(level) {
+		l.Helper()
+		l.inner.Logf(level, format, args...)
+	}
+}
+
+func (l *sampledLogger) Logln(level LogLevel, args ...any) {
+	if l.allow(level) {
+		l.Helper()
+		l.inner.Logln(level, args...)
+	}
+}
+
+func (l *sampledLogger) LogMessage(level LogLevel, message string) {
+	if l.allow(level) {
+		l.Helper()
+		l.inner.LogMessage(level, message)
+	}
+}
+
+// keyedSampledState is the rate-limiting state shared by all keys and all
+// WithField-derived loggers of one NewKeyedSampledLogger call.
+type keyedSampledState struct {
+	mu      sync.Mutex
+	buckets map[string]*sampleBucket
+	order   *list.List
+	elems   map[string]*list.Element
+}
+
+func newKeyedSampledState() *keyedSampledState {
+	return &keyedSampledState{
+		buckets: make(map[string]*sampleBucket),
+		order:   list.New(),
+		elems:   make(map[string]*list.Element),
+	}
+}
+
+func (s *keyedSampledState) allow(key string, level LogLevel, opts SampleOptions) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &sampleBucket{}
+		s.buckets[key] = b
+	}
+	s.touch(key, opts.maxKeys())
+
+	return opts.allow(b.hit(level, opts.tick(), time.Now()))
+}
+
+// touch marks key as most-recently-used and evicts the least-recently-used
+// key if that pushes the tracked set over maxKeys.
+func (s *keyedSampledState) touch(key string, maxKeys int) {
+	if el, ok := s.elems[key]; ok {
+		s.order.MoveToFront(el)
+	} else {
+		s.elems[key] = s.order.PushFront(key)
+	}
+	for s.order.Len() > maxKeys {
+		back := s.order.Back()
+		if back == nil {
+			return
+		}
+		evictedKey, _ := back.Value.(string)
+		s.order.Remove(back)
+		delete(s.elems, evictedKey)
+		delete(s.buckets, evictedKey)
+	}
+}
+
+// keyedSampledLogger decorates a Logger with per-key, per-level sampling.
+type keyedSampledLogger struct {
+	inner Logger
+	opts  SampleOptions
+	state *keyedSampledState
+	// key, if non-empty (set via SampleKey), overrides the per-call key
+	// that would otherwise be derived from the message/format string.
+	key string
+}
+
+var (
+	_ GenericLogger      = &keyedSampledLogger{}
+	_ LoggerWithMaxLevel = &keyedSampledLogger{}
+)
+
+// NewKeyedSampledLogger is like NewSampledLogger, except messages are
+// sampled independently per key, so that one repeated error doesn't eat the
+// budget for an unrelated one. The key is, by default, Logf's format string
+// or LogMessage's message--both cheap to key on without formatting anything;
+// Log and Logln have no such string and so share one fallback key unless an
+// explicit key is set for a scope with SampleKey. Key state is bounded by
+// opts.MaxKeys (default 1024) using LRU eviction.
+func NewKeyedSampledLogger(inner Logger, opts SampleOptions) Logger {
+	return &keyedSampledLogger{inner: inner, opts: opts, state: newKeyedSampledState()}
+}
+
+func (l *keyedSampledLogger) Helper() { l.inner.Helper() }
+
+func (l *keyedSampledLogger) StdLogger(level LogLevel) *log.Logger { return l.inner.StdLogger(level) }
+
+func (l *keyedSampledLogger) MaxLevel() LogLevel {
+	if lwm, ok := l.inner.(LoggerWithMaxLevel); ok {
+		return lwm.MaxLevel()
+	}
+	return LogLevelTrace
+}
+
+func (l *keyedSampledLogger) WithField(key string, value any) Logger {
+	return &keyedSampledLogger{inner: l.inner.WithField(key, value), opts: l.opts, state: l.state, key: l.key}
+}
+
+func (l *keyedSampledLogger) forKey(key string) Logger {
+	return &keyedSampledLogger{inner: l.inner, opts: l.opts, state: l.state, key: key}
+}
+
+func (l *keyedSampledLogger) allow(level LogLevel, fallbackKey string) bool {
+	key := l.key
+	if key == "" {
+		key = fallbackKey
+	}
+	ok := l.state.allow(key, level, l.opts)
+	if !ok && l.opts.OnDrop != nil {
+		l.opts.OnDrop(level)
+	}
+	return ok
+}
+
+// log/logln's callers (unlike Logf's) have no format string to key on, and
+// formatting args just to derive a key would defeat the point of sampling
+// dropped messages cheaply--so, absent an explicit SampleKey, they share one
+// fallback bucket instead of one per distinct message.
+const unkeyedLogFallback = ""
+
+func (l *keyedSampledLogger) Log(level LogLevel, args ...any) {
+	if l.allow(level, unkeyedLogFallback) {
+		l.Helper()
+		l.inner.Log(level, args...)
+	}
+}
+
+func (l *keyedSampledLogger) Logf(level LogLevel, format string, args ...any) {
+	if l.allow(level, format) {
+		l.Helper()
+		l.inner.Logf(level, format, args...)
+	}
+}
+
+func (l *keyedSampledLogger) Logln(level LogLevel, args ...any) {
+	if l.allow(level, unkeyedLogFallback) {
+		l.Helper()
+		l.inner.Logln(level, args...)
+	}
+}
+
+func (l *keyedSampledLogger) LogMessage(level LogLevel, message string) {
+	if l.allow(level, message) {
+		l.Helper()
+		l.inner.LogMessage(level, message)
+	}
+}
+
+// SampleKey returns a context whose Logger, if it (or something it wraps
+// via WithField) is a keyed sampled logger created by NewKeyedSampledLogger,
+// samples key independently of any other key. If there's no keyed sampled
+// logger in play, ctx is returned unchanged.
+func SampleKey(ctx context.Context, key string) context.Context {
+	if kl, ok := findKeyedSampledLogger(getLogger(ctx)); ok {
+		ctx = WithLogger(ctx, kl.forKey(key))
+	}
+	return ctx
+}
+
+func findKeyedSampledLogger(lg Logger) (*keyedSampledLogger, bool) {
+	for {
+		switch v := lg.(type) {
+		case *keyedSampledLogger:
+			return v, true
+		case *BaseLogger:
+			lg = v.GenericLogger
+		default:
+			return nil, false
+		}
+	}
+}
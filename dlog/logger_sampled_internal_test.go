@@ -0,0 +1,43 @@
+package dlog
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingPlainLogger is a minimal PlainLogger that counts LogMessage calls,
+// for tests internal to the package that need to reach unexported helpers
+// like getLogger.
+type recordingPlainLogger struct {
+	GenericImpl
+	count *int
+}
+
+func newRecordingPlainLogger() *recordingPlainLogger {
+	r := &recordingPlainLogger{count: new(int)}
+	r.GenericImpl = GenericImpl{PlainLogger: r}
+	return r
+}
+
+func (r *recordingPlainLogger) Helper() {}
+
+func (r *recordingPlainLogger) LogMessage(level LogLevel, message string) {
+	*r.count++
+}
+
+func TestSampleKey_GroupsCallsUnderExplicitKey(t *testing.T) {
+	rec := newRecordingPlainLogger()
+	sampled := NewKeyedSampledLogger(rec, SampleOptions{First: 1})
+
+	ctx := WithLogger(context.Background(), sampled)
+	ctx = SampleKey(ctx, "request-123")
+
+	// Without SampleKey these two distinct messages would each get their own
+	// First allowance; under the same explicit key they share one budget.
+	getLogger(ctx).LogMessage(LogLevelInfo, "alpha")
+	getLogger(ctx).LogMessage(LogLevelInfo, "beta")
+
+	if got, want := *rec.count, 1; got != want {
+		t.Fatalf("got %d messages, want %d", got, want)
+	}
+}
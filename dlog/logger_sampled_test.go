@@ -0,0 +1,82 @@
+package dlog_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/telepresenceio/dlib/v2/dlog"
+)
+
+func TestNewSampledLogger_FirstThenThereafter(t *testing.T) {
+	rec := newRecordingLogger()
+	sampled := dlog.NewSampledLogger(rec, dlog.SampleOptions{First: 2, Thereafter: 3})
+
+	for i := 0; i < 8; i++ {
+		sampled.LogMessage(dlog.LogLevelInfo, "hello")
+	}
+
+	// n=1,2 (First) logged; n=3,4 dropped; n=5 logged ((5-2)%3==0); n=6,7 dropped; n=8 logged.
+	if got, want := len(*rec.messages), 4; got != want {
+		t.Fatalf("got %d messages, want %d: %v", got, want, *rec.messages)
+	}
+}
+
+func TestNewSampledLogger_OnDropCalledForDroppedMessages(t *testing.T) {
+	rec := newRecordingLogger()
+	var dropped []dlog.LogLevel
+	sampled := dlog.NewSampledLogger(rec, dlog.SampleOptions{
+		First: 1,
+		OnDrop: func(level dlog.LogLevel) {
+			dropped = append(dropped, level)
+		},
+	})
+
+	sampled.LogMessage(dlog.LogLevelWarn, "one")
+	sampled.LogMessage(dlog.LogLevelWarn, "two")
+
+	if len(*rec.messages) != 1 {
+		t.Fatalf("got %d messages, want 1: %v", len(*rec.messages), *rec.messages)
+	}
+	if len(dropped) != 1 || dropped[0] != dlog.LogLevelWarn {
+		t.Fatalf("OnDrop calls = %v, want one LogLevelWarn", dropped)
+	}
+}
+
+func TestNewKeyedSampledLogger_KeysAreIndependent(t *testing.T) {
+	rec := newRecordingLogger()
+	sampled := dlog.NewKeyedSampledLogger(rec, dlog.SampleOptions{First: 1})
+
+	// Two distinct messages (keys) each get their own First allowance, even
+	// though a shared (unkeyed) budget would only have allowed one of them.
+	sampled.LogMessage(dlog.LogLevelInfo, "alpha")
+	sampled.LogMessage(dlog.LogLevelInfo, "alpha")
+	sampled.LogMessage(dlog.LogLevelInfo, "beta")
+	sampled.LogMessage(dlog.LogLevelInfo, "beta")
+
+	if got, want := len(*rec.messages), 2; got != want {
+		t.Fatalf("got %d messages, want %d: %v", got, want, *rec.messages)
+	}
+}
+
+func TestSampleKey_NoKeyedLoggerInPlayReturnsCtxUnchanged(t *testing.T) {
+	ctx := context.Background()
+	got := dlog.SampleKey(ctx, "some-key")
+	if got != ctx {
+		t.Fatal("SampleKey modified ctx despite no keyed sampled logger being in play")
+	}
+}
+
+func TestNewKeyedSampledLogger_MaxKeysEvictsLeastRecentlyUsed(t *testing.T) {
+	rec := newRecordingLogger()
+	sampled := dlog.NewKeyedSampledLogger(rec, dlog.SampleOptions{First: 1, MaxKeys: 2})
+
+	sampled.LogMessage(dlog.LogLevelInfo, "a") // logged; order: [a]
+	sampled.LogMessage(dlog.LogLevelInfo, "a") // dropped (n=2, First=1)
+	sampled.LogMessage(dlog.LogLevelInfo, "b") // logged; order: [b, a]
+	sampled.LogMessage(dlog.LogLevelInfo, "c") // logged; evicts "a"; order: [c, b]
+	sampled.LogMessage(dlog.LogLevelInfo, "a") // "a" was evicted, so its quota reset: logged again
+
+	if got, want := len(*rec.messages), 4; got != want {
+		t.Fatalf("got %d messages, want %d: %v", got, want, *rec.messages)
+	}
+}
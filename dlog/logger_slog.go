@@ -0,0 +1,244 @@
+package dlog
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+//nolint:gochecknoglobals // constant
+var dlogLevel2slogLevel = [5]slog.Level{
+	slog.LevelError,
+	slog.LevelWarn,
+	slog.LevelInfo,
+	slog.LevelDebug,
+	slog.LevelDebug - 4, // Trace is one notch below slog's Debug
+}
+
+func slogLevel(level LogLevel) slog.Level {
+	if level > LogLevelTrace {
+		panic(errors.Errorf("invalid LogLevel: %d", level))
+	}
+	return dlogLevel2slogLevel[level]
+}
+
+// dlogLevelFromSlog maps a slog.Level back onto a LogLevel. A custom level
+// between two of dlog's maps onto the more verbose of the two (e.g.
+// slog.LevelInfo+2 becomes LogLevelInfo), and anything below slog.LevelDebug
+// becomes LogLevelTrace.
+func dlogLevelFromSlog(level slog.Level) LogLevel {
+	for lvl := LogLevelError; lvl < LogLevelTrace; lvl++ {
+		if level >= dlogLevel2slogLevel[lvl] {
+			return lvl
+		}
+	}
+	return LogLevelTrace
+}
+
+// slogWrapper adapts a *slog.Logger into a Logger.
+type slogWrapper struct {
+	GenericImpl
+	logger *slog.Logger
+}
+
+var _ Logger = slogWrapper{}
+
+var _ LoggerWithMaxLevel = slogWrapper{}
+
+var _ callerContextLogger = slogWrapper{}
+
+func newSlogWrapper(logger *slog.Logger) slogWrapper {
+	w := slogWrapper{logger: logger}
+	w.GenericImpl = GenericImpl{PlainLogger: w}
+	return w
+}
+
+// WrapSlog converts a *slog.Logger into a generic Logger, mirroring
+// WrapLogrus.
+//
+// You should only really ever call WrapSlog from the initial process
+// set up (i.e. directly inside your 'main()' function), and you
+// should pass the result directly to WithLogger.
+func WrapSlog(in *slog.Logger) Logger {
+	return newSlogWrapper(in)
+}
+
+func (l slogWrapper) Helper() {}
+
+func (l slogWrapper) WithField(key string, value any) Logger {
+	return newSlogWrapper(l.logger.With(key, value))
+}
+
+func (l slogWrapper) LogMessage(level LogLevel, message string) {
+	l.logAt(context.Background(), level, message)
+}
+
+// logAt lets callers (notably the slog.Handler direction below) supply the
+// context that a record arrived with, so that e.g. a caller frame stashed on
+// it by Handle can flow through to the log entry.
+func (l slogWrapper) logAt(ctx context.Context, level LogLevel, message string) {
+	sl := slogLevel(level)
+	if !l.logger.Enabled(ctx, sl) {
+		return
+	}
+	var pc uintptr
+	if frame, ok := callerFrameFromContext(ctx); ok {
+		// A PC was stashed on ctx by slogHandler.Handle (forwarding a
+		// slog.Record's own PC); honor it instead of re-discovering one.
+		pc = frame.PC
+	} else if frame := getCaller(); frame != nil {
+		// Reuse the same frame-walk as the logrus wrapper, rather than a
+		// hardcoded skip count, so this stays correct no matter how many
+		// wrapper frames (dlog.Info, BaseLogger, a sampled logger, ...) sit
+		// between the caller and here.
+		pc = frame.PC
+	}
+	r := slog.NewRecord(time.Now(), sl, message, pc)
+	_ = l.logger.Handler().Handle(ctx, r)
+}
+
+func (l slogWrapper) StdLogger(level LogLevel) *log.Logger {
+	return slog.NewLogLogger(l.logger.Handler(), slogLevel(level))
+}
+
+func (l slogWrapper) MaxLevel() LogLevel {
+	ctx := context.Background()
+	for lvl := LogLevelTrace; lvl > LogLevelError; lvl-- {
+		if l.logger.Enabled(ctx, slogLevel(lvl)) {
+			return lvl
+		}
+	}
+	return LogLevelError
+}
+
+// callerContextLogger is implemented by Logger backends that can honor a
+// caller frame carried on the context (see callerFrameFromContext) instead of
+// re-discovering it by walking the current goroutine stack. The slog.Handler
+// returned by NewSlogHandler uses this so that a slog.Record's PC--which may
+// have been captured several stack frames away from where Handle runs--still
+// ends up as the reported caller.
+type callerContextLogger interface {
+	logAt(ctx context.Context, level LogLevel, message string)
+}
+
+type callerFrameCtxKey struct{}
+
+func contextWithCallerFrame(ctx context.Context, frame *runtime.Frame) context.Context {
+	return context.WithValue(ctx, callerFrameCtxKey{}, frame)
+}
+
+func callerFrameFromContext(ctx context.Context) (*runtime.Frame, bool) {
+	frame, ok := ctx.Value(callerFrameCtxKey{}).(*runtime.Frame)
+	return frame, ok && frame != nil
+}
+
+// resolvedAttr is a slog attr whose key has already been prefixed with
+// whatever group was active when it was added via WithAttrs, so that a later
+// WithGroup call can't retroactively re-scope it.
+type resolvedAttr struct {
+	key   string
+	value any
+}
+
+// slogHandler implements slog.Handler by forwarding records to whatever
+// Logger is attached to its context via WithLogger.
+type slogHandler struct {
+	ctx   context.Context
+	group string
+	attrs []resolvedAttr
+}
+
+// NewSlogHandler returns a slog.Handler that emits records into the Logger
+// attached to ctx (see WithLogger), so that library code can use log/slog
+// while still going through dlog's context-scoped logger.
+func NewSlogHandler(ctx context.Context) slog.Handler {
+	return &slogHandler{ctx: ctx}
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	lg := h.loggerFor(ctx)
+	if lwm, ok := lg.(LoggerWithMaxLevel); ok {
+		return dlogLevelFromSlog(level) <= lwm.MaxLevel()
+	}
+	return true
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	lg := h.loggerFor(ctx)
+	for _, ra := range h.attrs {
+		lg = lg.WithField(ra.key, ra.value)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		for _, ra := range resolveAttr(h.group, a) {
+			lg = lg.WithField(ra.key, ra.value)
+		}
+		return true
+	})
+	level := dlogLevelFromSlog(r.Level)
+	if r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ := frames.Next()
+		if cl, ok := lg.(callerContextLogger); ok {
+			cl.logAt(contextWithCallerFrame(ctx, &frame), level, r.Message)
+			return nil
+		}
+	}
+	lg.LogMessage(level, r.Message)
+	return nil
+}
+
+// resolveAttr flattens a (possibly nested-group) slog.Attr into one or more
+// resolvedAttrs, prefixing each key with group (if any). It's used both to
+// bake the then-current group into attrs added via WithAttrs, and to resolve
+// a record's own attrs against the handler's group at Handle time.
+func resolveAttr(group string, a slog.Attr) []resolvedAttr {
+	key := a.Key
+	if group != "" {
+		key = group + "." + key
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		var out []resolvedAttr
+		for _, ga := range a.Value.Group() {
+			out = append(out, resolveAttr(key, ga)...)
+		}
+		return out
+	}
+	return []resolvedAttr{{key: key, value: a.Value.Any()}}
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	n := *h
+	n.attrs = append([]resolvedAttr{}, h.attrs...)
+	for _, a := range attrs {
+		n.attrs = append(n.attrs, resolveAttr(h.group, a)...)
+	}
+	return &n
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	n := *h
+	if n.group != "" {
+		n.group += "." + name
+	} else {
+		n.group = name
+	}
+	return &n
+}
+
+func (h *slogHandler) loggerFor(ctx context.Context) Logger {
+	// The ctx a Handler method receives is usually not useful for logger
+	// resolution: slog.Logger.Info/Warn/Error/... (the non-Context
+	// variants--the common case) always pass a bare context.Background()
+	// here, which would otherwise silently shadow the Logger attached to
+	// the ctx NewSlogHandler was constructed with. So the constructor-bound
+	// ctx is the primary source; it's only not used if NewSlogHandler
+	// itself wasn't given one.
+	if h.ctx != nil {
+		ctx = h.ctx
+	}
+	return getLogger(ctx)
+}
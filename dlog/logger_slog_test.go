@@ -0,0 +1,127 @@
+package dlog_test
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/telepresenceio/dlib/v2/dlog"
+)
+
+// recordingLogger is a minimal GenericLogger that remembers every message it
+// was asked to log, along with the fields attached to it via WithField.
+type recordingLogger struct {
+	dlog.GenericImpl
+	messages  *[]string
+	fieldLogs *[]map[string]any
+	fields    map[string]any
+}
+
+func newRecordingLogger() *recordingLogger {
+	r := &recordingLogger{messages: new([]string), fieldLogs: new([]map[string]any), fields: map[string]any{}}
+	r.GenericImpl = dlog.GenericImpl{PlainLogger: r}
+	return r
+}
+
+func (r *recordingLogger) Helper() {}
+
+func (r *recordingLogger) LogMessage(level dlog.LogLevel, message string) {
+	*r.messages = append(*r.messages, message)
+	*r.fieldLogs = append(*r.fieldLogs, r.fields)
+}
+
+func (r *recordingLogger) WithField(key string, value any) dlog.Logger {
+	fields := map[string]any{key: value}
+	for k, v := range r.fields {
+		fields[k] = v
+	}
+	child := &recordingLogger{messages: r.messages, fieldLogs: r.fieldLogs, fields: fields}
+	child.GenericImpl = dlog.GenericImpl{PlainLogger: child}
+	return child
+}
+
+func TestNewSlogHandler_UsesConstructorBoundLogger(t *testing.T) {
+	rec := newRecordingLogger()
+	ctx := dlog.WithLogger(context.Background(), rec)
+
+	logger := slog.New(dlog.NewSlogHandler(ctx))
+	// The non-Context variant: slog passes context.Background() down to the
+	// Handler, not ctx. The handler must still find rec, since that's the
+	// whole point of binding ctx at construction time.
+	logger.Info("hello")
+
+	if len(*rec.messages) != 1 || (*rec.messages)[0] != "hello" {
+		t.Fatalf("messages = %v, want [\"hello\"]", *rec.messages)
+	}
+}
+
+func TestWrapSlog_ReportsRealCaller(t *testing.T) {
+	var gotFrame string
+	handler := &callerCapturingHandler{capture: &gotFrame}
+	logger := dlog.WrapSlog(slog.New(handler))
+
+	logCallerLine(logger)
+
+	if gotFrame == "" {
+		t.Fatal("no caller frame was captured")
+	}
+	if !strings.Contains(gotFrame, "logCallerLine") {
+		t.Fatalf("reported caller = %q, want it to mention logCallerLine", gotFrame)
+	}
+}
+
+func TestNewSlogHandler_WithGroupOnlyScopesLaterAttrs(t *testing.T) {
+	rec := newRecordingLogger()
+	ctx := dlog.WithLogger(context.Background(), rec)
+
+	logger := slog.New(dlog.NewSlogHandler(ctx))
+	// "a" is attached before WithGroup("g"), so it must stay top-level; only
+	// "b", attached after, should be nested under "g".
+	logger.With("a", 1).WithGroup("g").With("b", 2).Info("hello")
+
+	if len(*rec.fieldLogs) != 1 {
+		t.Fatalf("got %d logged records, want 1", len(*rec.fieldLogs))
+	}
+	fields := (*rec.fieldLogs)[0]
+
+	if _, ok := fields["a"]; !ok {
+		t.Errorf("fields = %v, want top-level key %q (added before WithGroup)", fields, "a")
+	}
+	if _, ok := fields["g.a"]; ok {
+		t.Errorf("fields = %v, key %q was wrongly scoped under the later WithGroup", fields, "g.a")
+	}
+	if _, ok := fields["g.b"]; !ok {
+		t.Errorf("fields = %v, want key %q (added after WithGroup(\"g\"))", fields, "g.b")
+	}
+}
+
+// logCallerLine exists so the caller-reporting test above calls through an
+// extra stack frame, the way dlog.Info/BaseLogger/a sampled logger would in
+// real usage--this is exactly the case a hardcoded stack-skip count gets
+// wrong.
+func logCallerLine(logger dlog.Logger) {
+	logger.Log(dlog.LogLevelInfo, "hi")
+}
+
+// callerCapturingHandler is a slog.Handler that records the function name of
+// the reported caller for each record it receives.
+type callerCapturingHandler struct {
+	capture *string
+}
+
+func (h *callerCapturingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *callerCapturingHandler) Handle(_ context.Context, r slog.Record) error {
+	if r.PC != 0 {
+		frames := runtime.CallersFrames([]uintptr{r.PC})
+		frame, _ := frames.Next()
+		*h.capture = frame.Function
+	}
+	return nil
+}
+
+func (h *callerCapturingHandler) WithAttrs(attrs []slog.Attr) slog.Handler { return h }
+
+func (h *callerCapturingHandler) WithGroup(name string) slog.Handler { return h }
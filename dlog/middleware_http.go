@@ -0,0 +1,26 @@
+package dlog
+
+import "net/http"
+
+// HTTPMiddleware reads a correlation ID from the first configured header
+// present on the request (see SetCorrelationIDHeaders), generating one if
+// none is present, and attaches it to the request's context via
+// WithCorrelationID before calling next.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := correlationIDFromHeader(r.Header)
+		if id == "" {
+			id = generateCorrelationID()
+		}
+		next.ServeHTTP(w, r.WithContext(WithCorrelationID(r.Context(), id)))
+	})
+}
+
+func correlationIDFromHeader(h http.Header) string {
+	for _, name := range configuredCorrelationIDHeaders() {
+		if id := h.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}